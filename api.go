@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxPageSize caps ?size= the same way Subsonic caps album list requests, so
+// a single request can't force the server to serialize the entire catalog.
+const maxPageSize = 500
+
+// defaultPageSize is used when ?size= is absent or invalid.
+const defaultPageSize = 50
+
+// apiRoutes builds the headless JSON API mounted under /api/v1.
+func (a *App) apiRoutes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/artists", a.requireReady(a.listArtistsAPI))
+	r.Get("/artists/{id}", a.requireReady(a.getArtistAPI))
+	r.Get("/artists/{id}/locations", a.requireReady(a.getArtistFieldAPI("Locations")))
+	r.Get("/artists/{id}/dates", a.requireReady(a.getArtistFieldAPI("Dates")))
+	r.Get("/artists/{id}/relation", a.requireReady(a.getArtistFieldAPI("Relation")))
+	r.Get("/artists/{id}/geo", a.requireReady(a.getArtistGeoAPI))
+	r.Get("/search", a.requireReady(a.searchHandler))
+	return r
+}
+
+// writeJSON encodes v as the response body with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// paginationParams reads ?page= and ?size= from the request, defaulting and
+// clamping size to [1, maxPageSize].
+func paginationParams(r *http.Request) (page, size int) {
+	page, _ = strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	size, _ = strconv.Atoi(r.URL.Query().Get("size"))
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	return page, size
+}
+
+// artistsPage is the paginated envelope returned by GET /api/v1/artists.
+type artistsPage struct {
+	Page    int                      `json:"page"`
+	Size    int                      `json:"size"`
+	Total   int                      `json:"total"`
+	Artists []map[string]interface{} `json:"artists"`
+}
+
+// listArtistsAPI handles GET /api/v1/artists.
+func (a *App) listArtistsAPI(w http.ResponseWriter, r *http.Request) {
+	artists := a.store.Get()
+	page, size := paginationParams(r)
+
+	// Clamp page before multiplying by size, so an oversized ?page= can't
+	// overflow start into a negative index.
+	maxPage := (len(artists) + size - 1) / size
+	if maxPage < 1 {
+		maxPage = 1
+	}
+	if page > maxPage {
+		page = maxPage
+	}
+
+	start := (page - 1) * size
+	if start > len(artists) {
+		start = len(artists)
+	}
+	end := start + size
+	if end > len(artists) {
+		end = len(artists)
+	}
+
+	writeJSON(w, http.StatusOK, artistsPage{
+		Page:    page,
+		Size:    size,
+		Total:   len(artists),
+		Artists: artists[start:end],
+	})
+}
+
+// lookupArtist resolves the {id} path parameter against the store, writing
+// a 404 and returning ok=false if it doesn't refer to a known artist.
+func (a *App) lookupArtist(w http.ResponseWriter, r *http.Request) (artist map[string]interface{}, ok bool) {
+	artists := a.store.Get()
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id < 1 || id > len(artists) {
+		http.Error(w, "404 ! Artist not found", http.StatusNotFound)
+		return nil, false
+	}
+	return artists[id-1], true
+}
+
+// getArtistAPI handles GET /api/v1/artists/{id}.
+func (a *App) getArtistAPI(w http.ResponseWriter, r *http.Request) {
+	artist, ok := a.lookupArtist(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, artist)
+}
+
+// getArtistFieldAPI returns a handler for GET /api/v1/artists/{id}/<field>,
+// serving a single field out of the artist's map (e.g. "Locations").
+func (a *App) getArtistFieldAPI(field string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		artist, ok := a.lookupArtist(w, r)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, artist[field])
+	}
+}
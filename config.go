@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"time"
+)
+
+// Config holds the runtime configuration for the server. Every field can be
+// set via an environment variable, with a matching flag taking precedence.
+type Config struct {
+	Addr            string
+	TemplatesDir    string
+	AssetsDir       string
+	APIBaseURL      string
+	RefreshInterval time.Duration
+}
+
+// loadConfig reads Config from the ADDR, TEMPLATES_DIR, ASSETS_DIR,
+// API_BASE_URL and REFRESH_INTERVAL environment variables, then lets the
+// matching command-line flag override each of them.
+func loadConfig() Config {
+	cfg := Config{
+		Addr:            envOrDefault("ADDR", ":8080"),
+		TemplatesDir:    envOrDefault("TEMPLATES_DIR", "templates"),
+		AssetsDir:       envOrDefault("ASSETS_DIR", "assets"),
+		APIBaseURL:      envOrDefault("API_BASE_URL", "https://groupietrackers.herokuapp.com/api"),
+		RefreshInterval: envDurationOrDefault("REFRESH_INTERVAL", 15*time.Minute),
+	}
+
+	flag.StringVar(&cfg.Addr, "addr", cfg.Addr, "address to listen on")
+	flag.StringVar(&cfg.TemplatesDir, "templates-dir", cfg.TemplatesDir, "directory containing HTML templates")
+	flag.StringVar(&cfg.AssetsDir, "assets-dir", cfg.AssetsDir, "directory containing static assets")
+	flag.StringVar(&cfg.APIBaseURL, "api-base-url", cfg.APIBaseURL, "base URL of the upstream Groupie Trackers API")
+	flag.DurationVar(&cfg.RefreshInterval, "refresh-interval", cfg.RefreshInterval, "interval between background data refreshes")
+	flag.Parse()
+
+	return cfg
+}
+
+// envOrDefault returns the value of the environment variable key, or def if
+// it's unset or empty.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// envDurationOrDefault returns the parsed duration of the environment
+// variable key, or def if it's unset or invalid.
+func envDurationOrDefault(key string, def time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return def
+}
@@ -0,0 +1,105 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// cacheEntry is one cached outcome: either a resolved Point, or a recorded
+// "not found" result (Unresolved) so a slug the provider genuinely has no
+// match for isn't retried against the upstream API on every request.
+// Transport/status errors are never cached here - they may succeed on
+// retry, so Geocode just returns them without writing an entry.
+type cacheEntry struct {
+	Point      Point  `json:"point,omitempty"`
+	Unresolved bool   `json:"unresolved,omitempty"`
+	Err        string `json:"err,omitempty"`
+}
+
+// CachingGeocoder wraps a Geocoder with a persistent on-disk JSON cache, so
+// repeated process startups don't re-hit the upstream API for slugs that
+// have already been resolved, or keep retrying ones the provider has
+// confirmed it has no match for.
+type CachingGeocoder struct {
+	next Geocoder
+	path string
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewCachingGeocoder builds a CachingGeocoder backed by next, loading any
+// existing cache at path. A missing file is treated as an empty cache.
+func NewCachingGeocoder(next Geocoder, path string) (*CachingGeocoder, error) {
+	c := &CachingGeocoder{next: next, path: path, cache: make(map[string]cacheEntry)}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("geo: reading cache: %w", err)
+	}
+	if err := json.Unmarshal(b, &c.cache); err != nil {
+		return nil, fmt.Errorf("geo: decoding cache: %w", err)
+	}
+	return c, nil
+}
+
+// Geocode returns the cached outcome for slug if known - a Point, or the
+// recorded error for a slug the provider previously reported no match for -
+// otherwise resolves it via next. Only a genuine ErrNotFound result is
+// cached; transport/status errors are passed through uncached so a
+// transient failure gets retried on the next request.
+func (c *CachingGeocoder) Geocode(ctx context.Context, slug string) (Point, error) {
+	c.mu.Lock()
+	if entry, ok := c.cache[slug]; ok {
+		c.mu.Unlock()
+		if entry.Unresolved {
+			return Point{}, fmt.Errorf("%w: %q (cached): %s", ErrNotFound, slug, entry.Err)
+		}
+		return entry.Point, nil
+	}
+	c.mu.Unlock()
+
+	p, err := c.next.Geocode(ctx, slug)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return Point{}, err
+		}
+
+		c.mu.Lock()
+		c.cache[slug] = cacheEntry{Unresolved: true, Err: err.Error()}
+		saveErr := c.save()
+		c.mu.Unlock()
+		if saveErr != nil {
+			return Point{}, err
+		}
+		return Point{}, err
+	}
+
+	c.mu.Lock()
+	c.cache[slug] = cacheEntry{Point: p}
+	saveErr := c.save()
+	c.mu.Unlock()
+	if saveErr != nil {
+		return p, saveErr
+	}
+	return p, nil
+}
+
+// save writes the cache to disk. Callers must hold c.mu.
+func (c *CachingGeocoder) save() error {
+	b, err := json.MarshalIndent(c.cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("geo: encoding cache: %w", err)
+	}
+	if err := os.WriteFile(c.path, b, 0o644); err != nil {
+		return fmt.Errorf("geo: writing cache: %w", err)
+	}
+	return nil
+}
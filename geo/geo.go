@@ -0,0 +1,40 @@
+// Package geo resolves Groupie Trackers location slugs (e.g.
+// "north_carolina-usa") to coordinates.
+package geo
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Point is a resolved latitude/longitude pair for a location slug.
+type Point struct {
+	Slug string
+	Lat  float64
+	Lon  float64
+}
+
+// ErrNotFound indicates the geocoding provider understood the request but
+// has no result for the slug - a genuine absence, as opposed to a
+// transport/status error that may succeed on retry. Geocoder implementations
+// should wrap this error so CachingGeocoder can tell the two apart.
+var ErrNotFound = errors.New("geo: no results for location")
+
+// Geocoder resolves a location slug to a Point. ctx is honored for
+// cancellation (e.g. a client disconnecting mid-request) and does not
+// override the provider's own rate limiting.
+type Geocoder interface {
+	Geocode(ctx context.Context, slug string) (Point, error)
+}
+
+// ParseSlug turns a "north_carolina-usa" style slug into a human-readable,
+// comma-separated query string suitable for a geocoding API, e.g.
+// "north carolina, usa".
+func ParseSlug(slug string) string {
+	parts := strings.Split(slug, "-")
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(p, "_", " ")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,110 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// nominatimMinGap is the minimum time between requests, per Nominatim's
+// usage policy of at most one request per second.
+const nominatimMinGap = time.Second
+
+// NominatimGeocoder resolves slugs via OpenStreetMap's Nominatim search API.
+// It sends the required descriptive User-Agent and rate-limits itself to
+// stay within Nominatim's usage policy.
+type NominatimGeocoder struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+
+	mu       sync.Mutex
+	lastCall time.Time
+}
+
+// NewNominatimGeocoder builds a NominatimGeocoder. userAgent should identify
+// the application and a contact method, as required by Nominatim's usage
+// policy.
+func NewNominatimGeocoder(userAgent string) *NominatimGeocoder {
+	return &NominatimGeocoder{
+		baseURL:   "https://nominatim.openstreetmap.org/search",
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Geocode resolves slug to a Point by querying Nominatim's search endpoint.
+// It returns an error wrapping ErrNotFound when Nominatim has no match for
+// slug, distinct from transport/status errors that may succeed on retry.
+func (g *NominatimGeocoder) Geocode(ctx context.Context, slug string) (Point, error) {
+	if err := g.throttle(ctx); err != nil {
+		return Point{}, err
+	}
+
+	query := ParseSlug(slug)
+	reqURL := fmt.Sprintf("%s?q=%s&format=json&limit=1", g.baseURL, url.QueryEscape(query))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return Point{}, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return Point{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Point{}, fmt.Errorf("geo: nominatim returned %s for %q", resp.Status, slug)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return Point{}, fmt.Errorf("geo: decoding nominatim response for %q: %w", slug, err)
+	}
+	if len(results) == 0 {
+		return Point{}, fmt.Errorf("%w: %q", ErrNotFound, slug)
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("geo: parsing latitude for %q: %w", slug, err)
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("geo: parsing longitude for %q: %w", slug, err)
+	}
+
+	return Point{Slug: slug, Lat: lat, Lon: lon}, nil
+}
+
+// throttle blocks until at least nominatimMinGap has passed since the
+// previous call, or returns early with ctx's error if it's cancelled first.
+func (g *NominatimGeocoder) throttle(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	wait := nominatimMinGap - time.Since(g.lastCall)
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	g.lastCall = time.Now()
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+)
+
+// geoCacheFile is where the on-disk geocoding cache is persisted.
+const geoCacheFile = "geocache.json"
+
+// geoFeatureCollection is a minimal GeoJSON FeatureCollection, per
+// https://datatracker.ietf.org/doc/html/rfc7946.
+type geoFeatureCollection struct {
+	Type       string       `json:"type"`
+	Features   []geoFeature `json:"features"`
+	Unresolved []string     `json:"unresolved,omitempty"`
+}
+
+type geoFeature struct {
+	Type       string        `json:"type"`
+	Geometry   geoGeometry   `json:"geometry"`
+	Properties geoProperties `json:"properties"`
+}
+
+type geoGeometry struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type geoProperties struct {
+	Location string   `json:"location"`
+	Dates    []string `json:"dates"`
+}
+
+// getArtistGeoAPI handles GET /api/v1/artists/{id}/geo, resolving the
+// artist's concert location slugs to coordinates and annotating each with
+// its concert dates from the Relation map. Slugs that can't be resolved are
+// reported under "unresolved" rather than failing the whole request.
+func (a *App) getArtistGeoAPI(w http.ResponseWriter, r *http.Request) {
+	artist, ok := a.lookupArtist(w, r)
+	if !ok {
+		return
+	}
+
+	locations := artist["Locations"].([]string)
+	relation, _ := artist["Relation"].(map[string][]string)
+	ctx := r.Context()
+
+	fc := geoFeatureCollection{Type: "FeatureCollection", Features: []geoFeature{}}
+	for _, loc := range locations {
+		if ctx.Err() != nil {
+			return
+		}
+
+		point, err := a.geocoder.Geocode(ctx, loc)
+		if err != nil {
+			fc.Unresolved = append(fc.Unresolved, loc)
+			continue
+		}
+
+		fc.Features = append(fc.Features, geoFeature{
+			Type:       "Feature",
+			Geometry:   geoGeometry{Type: "Point", Coordinates: [2]float64{point.Lon, point.Lat}},
+			Properties: geoProperties{Location: loc, Dates: relation[loc]},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, fc)
+}
+
+// mapHandler renders the /map page, which plots every artist's concert
+// locations with Leaflet + MarkerCluster, fetching coordinates from
+// /api/v1/artists/{id}/geo.
+func (a *App) mapHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "405 ! Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := a.templates.ExecuteTemplate(w, "map.html", a.store.Get()); err != nil {
+		http.Error(w, "500 ! "+err.Error(), http.StatusInternalServerError)
+	}
+}
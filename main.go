@@ -1,20 +1,25 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
-	"strings"
 	"sync"
-)
-
-// Global variables to hold data
-var (
-	artistData []map[string]interface{} // Holds processed artist data
-	templates  *template.Template       // Holds parsed HTML templates
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+	"github.com/ibentour/Groupie-tracker/geo"
 )
 
 // Data structures for JSON parsing
@@ -66,77 +71,222 @@ type (
 	}
 )
 
-// fetchData retrieves data from a given URL and decodes it into the target structure.
-func fetchData(url string, target interface{}) error {
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
+// ArtistStore holds the processed artist data, and the SearchIndex built
+// over it, behind atomic.Pointers so handlers always see a consistent
+// snapshot without locking, even while the Refresher swaps in new ones in
+// the background.
+type ArtistStore struct {
+	data  atomic.Pointer[[]map[string]interface{}]
+	index atomic.Pointer[SearchIndex]
+}
+
+// Get returns the current snapshot of artist data.
+func (s *ArtistStore) Get() []map[string]interface{} {
+	if data := s.data.Load(); data != nil {
+		return *data
 	}
-	defer resp.Body.Close()
-	return json.NewDecoder(resp.Body).Decode(target)
+	return nil
 }
 
-// gatherData fetches and organizes data into a slice of maps.
-func gatherData() error {
-	var (
-		data      Data
-		artists   []Artist
-		locations Locations
-		relation  Relation
-		dates     Dates
-	)
+// Index returns the SearchIndex built over the current snapshot.
+func (s *ArtistStore) Index() *SearchIndex {
+	return s.index.Load()
+}
+
+// Set replaces the stored artist data and rebuilds the SearchIndex over it.
+func (s *ArtistStore) Set(data []map[string]interface{}) {
+	s.data.Store(&data)
+	s.index.Store(NewSearchIndex(data))
+}
 
-	// Fetch the main API data
-	if err := fetchData("https://groupietrackers.herokuapp.com/api", &data); err != nil {
-		return err
+// App bundles the server's dependencies so handlers can be methods on it
+// instead of relying on package-level state.
+type App struct {
+	cfg       Config
+	mux       *chi.Mux
+	templates *template.Template
+	store     *ArtistStore
+	refresher *Refresher
+	geocoder  geo.Geocoder
+	logger    *slog.Logger
+}
+
+// NewApp builds an App and parses the templates. Unlike the old
+// init()/global-variable setup, it no longer blocks on the initial data
+// fetch: that happens in the background once the Refresher is started, and
+// handlers report 503 via requireReady until it completes.
+func NewApp(cfg Config, logger *slog.Logger) (*App, error) {
+	funcMap := template.FuncMap{
+		"toString": func(v interface{}) string {
+			return fmt.Sprintf("%v", v)
+		},
 	}
 
-	// Use a WaitGroup to fetch data concurrently
-	var wg sync.WaitGroup
-	var errs []error
-	wg.Add(4)
+	templates, err := template.New("").Funcs(funcMap).ParseGlob(cfg.TemplatesDir + "/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("parsing templates: %w", err)
+	}
 
-	// Fetch artists data
-	go func() {
-		defer wg.Done()
-		if err := fetchData(data.Artists, &artists); err != nil {
-			errs = append(errs, err)
-		}
-	}()
+	geocoder, err := geo.NewCachingGeocoder(geo.NewNominatimGeocoder(nominatimUserAgent), geoCacheFile)
+	if err != nil {
+		return nil, fmt.Errorf("building geocoder: %w", err)
+	}
 
-	// Fetch locations data
-	go func() {
-		defer wg.Done()
-		if err := fetchData(data.Locations, &locations); err != nil {
-			errs = append(errs, err)
-		}
-	}()
+	store := &ArtistStore{}
+	app := &App{
+		cfg:       cfg,
+		mux:       chi.NewRouter(),
+		templates: templates,
+		store:     store,
+		refresher: NewRefresher(store, cfg.RefreshInterval, cfg.APIBaseURL),
+		geocoder:  geocoder,
+		logger:    logger,
+	}
 
-	// Fetch relation data
-	go func() {
-		defer wg.Done()
-		if err := fetchData(data.Relation, &relation); err != nil {
-			errs = append(errs, err)
+	app.routes()
+	return app, nil
+}
+
+// nominatimUserAgent identifies this application to Nominatim, per its
+// usage policy (https://operations.osmfoundation.org/policies/nominatim/).
+const nominatimUserAgent = "groupie-tracker/1.0 (+https://github.com/ibentour/Groupie-tracker)"
+
+// routes registers all HTTP handlers on the App's mux.
+func (a *App) routes() {
+	a.mux.Use(middleware.RequestID)
+	a.mux.Use(a.requestLogger)
+	a.mux.Use(middleware.Recoverer)
+	a.mux.Use(middleware.Compress(5))
+	a.mux.Use(cors.Handler(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodOptions},
+		AllowedHeaders: []string{"Accept", "Content-Type", "If-None-Match"},
+	}))
+
+	a.mux.Handle("/assets/*", http.StripPrefix("/assets/", http.FileServer(http.Dir(a.cfg.AssetsDir))))
+	a.mux.HandleFunc("/", a.requireReady(a.indexHandler))
+	a.mux.HandleFunc("/Artist", a.requireReady(a.singleArtistHandler))
+	a.mux.HandleFunc("/about", a.aboutHandler)
+	a.mux.HandleFunc("/search", a.requireReady(a.searchHandler))
+	a.mux.HandleFunc("/map", a.requireReady(a.mapHandler))
+	a.mux.HandleFunc("/healthz", a.healthzHandler)
+	a.mux.HandleFunc("/readyz", a.readyzHandler)
+
+	a.mux.Mount("/api/v1", a.apiRoutes())
+}
+
+// requestLogger logs every request as structured JSON via slog, recording
+// the request id, method, path, status and latency.
+func (a *App) requestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		a.logger.Info("request",
+			"request_id", middleware.GetReqID(r.Context()),
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", ww.Status(),
+			"latency", time.Since(start).String(),
+		)
+	})
+}
+
+// requireReady wraps a handler so it returns 503 instead of running against
+// an empty store before the first refresh has completed.
+func (a *App) requireReady(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.refresher.Ready() {
+			http.Error(w, "503 ! Not ready, initial data fetch still in progress", http.StatusServiceUnavailable)
+			return
 		}
-	}()
+		next(w, r)
+	}
+}
 
-	// Fetch dates data
-	go func() {
-		defer wg.Done()
-		if err := fetchData(data.Dates, &dates); err != nil {
-			errs = append(errs, err)
+// healthzHandler reports process liveness.
+func (a *App) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "ok")
+}
+
+// readyzHandler reports whether the store holds data from a successful
+// refresh, along with the outcome of the most recent refresh attempt.
+func (a *App) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	status := a.refresher.Status()
+
+	resp := struct {
+		Ready       bool      `json:"ready"`
+		LastSuccess time.Time `json:"lastSuccess,omitempty"`
+		LastError   string    `json:"lastError,omitempty"`
+	}{
+		Ready:       a.refresher.Ready(),
+		LastSuccess: status.LastSuccess,
+	}
+	if status.LastError != nil {
+		resp.LastError = status.LastError.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !resp.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// gatherData fetches and organizes data into a slice of maps. The four
+// upstream endpoints are fetched concurrently; every goroutine reports its
+// error (if any) under a mutex, and all of them are joined via errors.Join
+// instead of racing on a shared slice or discarding all but the first. fetch
+// is injected so callers can plug in ETag-aware caching (see Refresher).
+func gatherData(fetch func(url string, target interface{}) error, apiBaseURL string) ([]map[string]interface{}, error) {
+	var data Data
+	if err := fetch(apiBaseURL, &data); err != nil {
+		return nil, err
+	}
+
+	var (
+		artists   []Artist
+		locations Locations
+		relation  Relation
+		dates     Dates
+	)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		fetches = []struct {
+			url    string
+			target interface{}
+		}{
+			{data.Artists, &artists},
+			{data.Locations, &locations},
+			{data.Relation, &relation},
+			{data.Dates, &dates},
 		}
-	}()
+		errs []error
+	)
 
-	// Wait for all goroutines to finish
+	wg.Add(len(fetches))
+	for _, f := range fetches {
+		go func(url string, target interface{}) {
+			defer wg.Done()
+			if err := fetch(url, target); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(f.url, f.target)
+	}
 	wg.Wait()
 
-	// Check if any errors occurred during fetching
-	if len(errs) > 0 {
-		return errs[0] // Return the first error encountered
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
 	}
 
-	// Process and combine all data into artistData
+	artistData := make([]map[string]interface{}, 0, len(artists))
 	for x := 0; x < len(artists); x++ {
 		artistData = append(artistData, map[string]interface{}{
 			"Id":           artists[x].ID,
@@ -151,11 +301,11 @@ func gatherData() error {
 		})
 	}
 
-	return nil
+	return artistData, nil
 }
 
 // indexHandler handles HTTP requests for the home page ("/").
-func indexHandler(w http.ResponseWriter, r *http.Request) {
+func (a *App) indexHandler(w http.ResponseWriter, r *http.Request) {
 	// Check if the request method is GET
 	if r.Method != http.MethodGet {
 		http.Error(w, "405 ! Method not allowed", http.StatusMethodNotAllowed)
@@ -165,26 +315,28 @@ func indexHandler(w http.ResponseWriter, r *http.Request) {
 	// Ensure we're on the root path
 	if r.URL.Path != "/" {
 		// Execute the 404 template
-		if err := templates.ExecuteTemplate(w, "404.html", nil); err != nil {
+		if err := a.templates.ExecuteTemplate(w, "404.html", nil); err != nil {
 			http.Error(w, "500 ! "+err.Error(), http.StatusInternalServerError)
 		}
 		return
 	}
 
 	// Execute the index template with the artist data
-	if err := templates.ExecuteTemplate(w, "index.html", artistData); err != nil {
+	if err := a.templates.ExecuteTemplate(w, "index.html", a.store.Get()); err != nil {
 		http.Error(w, "500 ! "+err.Error(), http.StatusInternalServerError)
 	}
 }
 
 // singleArtistHandler handles HTTP requests for individual artist details.
-func singleArtistHandler(w http.ResponseWriter, r *http.Request) {
+func (a *App) singleArtistHandler(w http.ResponseWriter, r *http.Request) {
 	// Check if the request method is GET
 	if r.Method != http.MethodGet {
 		http.Error(w, "405 ! Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	artistData := a.store.Get()
+
 	// Parse and validate the artist ID from the URL query
 	artistID, err := strconv.Atoi(r.URL.Query().Get("id"))
 	if err != nil || artistID < 1 || artistID > len(artistData) {
@@ -193,13 +345,13 @@ func singleArtistHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute the artist template with the specific artist's data
-	if err := templates.ExecuteTemplate(w, "artist.html", artistData[artistID-1]); err != nil {
+	if err := a.templates.ExecuteTemplate(w, "artist.html", artistData[artistID-1]); err != nil {
 		http.Error(w, "500 ! "+err.Error(), http.StatusInternalServerError)
 	}
 }
 
 // aboutHandler handles HTTP requests for the about page ("/about").
-func aboutHandler(w http.ResponseWriter, r *http.Request) {
+func (a *App) aboutHandler(w http.ResponseWriter, r *http.Request) {
 	// Check if the request method is GET
 	if r.Method != http.MethodGet {
 		http.Error(w, "405 ! Method not allowed", http.StatusMethodNotAllowed)
@@ -207,93 +359,52 @@ func aboutHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute the about template
-	if err := templates.ExecuteTemplate(w, "about.html", nil); err != nil {
+	if err := a.templates.ExecuteTemplate(w, "about.html", nil); err != nil {
 		http.Error(w, "500 ! "+err.Error(), http.StatusInternalServerError)
 	}
 }
 
-// init is called before main() to set up the application
-func init() {
-	// Fetch all data at startup
-	if err := gatherData(); err != nil {
-		log.Fatalf("Failed to gather data: %v", err)
-	}
-
-	// Set up template functions
-	funcMap := template.FuncMap{
-		"toString": func(v interface{}) string {
-			return fmt.Sprintf("%v", v)
-		},
-	}
-
-	// Parse HTML templates
-	var err error
-	templates, err = template.New("").Funcs(funcMap).ParseGlob("templates/*.html")
-	if err != nil {
-		log.Fatalf("Failed to parse the HTML templates: %v", err)
-	}
+// searchHandler handles search requests, combining free-text matching with
+// the structured filters and ranking implemented in search.go.
+func (a *App) searchHandler(w http.ResponseWriter, r *http.Request) {
+	filters := parseSearchFilters(r)
+	results := a.store.Index().Search(filters)
+	writeJSON(w, http.StatusOK, results)
 }
 
 // main is the entry point of the application
 func main() {
-	// Serve static files (CSS)
-	http.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.Dir("./assets"))))
-	// Set up route handlers
-	http.HandleFunc("/", indexHandler)
-	http.HandleFunc("/Artist", singleArtistHandler)
-	http.HandleFunc("/about", aboutHandler)
-	http.HandleFunc("/search", searchHandler)
-
-	// Start the HTTP server
-	fmt.Println("Server started at :8080")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		log.Fatal(err)
-	}
-}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
 
-// Add this new struct to your existing types
-type SearchResult struct {
-	Type  string `json:"type"`
-	Value string `json:"value"`
-	ID    int    `json:"id"`
-}
+	cfg := loadConfig()
 
-// Add this new function to handle search requests
-func searchHandler(w http.ResponseWriter, r *http.Request) {
-	query := strings.ToLower(r.URL.Query().Get("q"))
-	var results []SearchResult
+	app, err := NewApp(cfg, logger)
+	if err != nil {
+		logger.Error("failed to build app", "error", err)
+		os.Exit(1)
+	}
 
-	for _, artist := range artistData {
-		// Search by artist name
-		if strings.Contains(strings.ToLower(artist["Name"].(string)), query) {
-			results = append(results, SearchResult{Type: "artist", Value: artist["Name"].(string), ID: artist["Id"].(int)})
-		}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-		// Search by members
-		for _, member := range artist["Members"].([]string) {
-			if strings.Contains(strings.ToLower(member), query) {
-				results = append(results, SearchResult{Type: "member", Value: member, ID: artist["Id"].(int)})
-			}
-		}
+	go app.refresher.Run(ctx)
 
-		// Search by locations
-		for _, location := range artist["Locations"].([]string) {
-			if strings.Contains(strings.ToLower(location), query) {
-				results = append(results, SearchResult{Type: "location", Value: location, ID: artist["Id"].(int)})
-			}
+	srv := &http.Server{Addr: cfg.Addr, Handler: app.mux}
+	go func() {
+		logger.Info("server started", "addr", cfg.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("server error", "error", err)
+			os.Exit(1)
 		}
+	}()
 
-		// Search by first album date
-		if strings.Contains(strings.ToLower(artist["FirstAlbum"].(string)), query) {
-			results = append(results, SearchResult{Type: "first album", Value: artist["FirstAlbum"].(string), ID: artist["Id"].(int)})
-		}
+	<-ctx.Done()
+	logger.Info("shutting down")
 
-		// Search by creation date
-		if strings.Contains(strconv.Itoa(artist["CreationDate"].(int)), query) {
-			results = append(results, SearchResult{Type: "creation date", Value: strconv.Itoa(artist["CreationDate"].(int)), ID: artist["Id"].(int)})
-		}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("graceful shutdown failed", "error", err)
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
 }
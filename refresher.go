@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RefreshStatus reports the outcome of the most recent background refresh.
+type RefreshStatus struct {
+	LastSuccess time.Time
+	LastError   error
+}
+
+// Refresher periodically re-runs gatherData and publishes the result into an
+// ArtistStore, so the process never needs restarting when the upstream API
+// updates or a single fetch is flaky. It keeps the last ETag seen per URL and
+// sends If-None-Match on subsequent requests, skipping the re-decode when the
+// upstream reports the payload hasn't changed.
+type Refresher struct {
+	store      *ArtistStore
+	interval   time.Duration
+	apiBaseURL string
+	client     *http.Client
+
+	mu     sync.Mutex
+	etags  map[string]string
+	bodies map[string][]byte
+
+	status atomic.Pointer[RefreshStatus]
+}
+
+// NewRefresher builds a Refresher that writes into store every interval,
+// fetching the index document from apiBaseURL.
+func NewRefresher(store *ArtistStore, interval time.Duration, apiBaseURL string) *Refresher {
+	return &Refresher{
+		store:      store,
+		interval:   interval,
+		apiBaseURL: apiBaseURL,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		etags:      make(map[string]string),
+		bodies:     make(map[string][]byte),
+	}
+}
+
+// fetch retrieves url and decodes it into target. It sends If-None-Match
+// when a previous ETag is known, and reuses the cached body on a 304
+// response instead of re-decoding an unchanged payload.
+func (r *Refresher) fetch(url string, target interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	etag := r.etags[url]
+	r.mu.Unlock()
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		r.mu.Lock()
+		body := r.bodies[url]
+		r.mu.Unlock()
+		return json.Unmarshal(body, target)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching %s: unexpected status %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		r.mu.Lock()
+		r.etags[url] = etag
+		r.bodies[url] = body
+		r.mu.Unlock()
+	}
+
+	return json.Unmarshal(body, target)
+}
+
+// Status returns the outcome of the most recent refresh.
+func (r *Refresher) Status() RefreshStatus {
+	if s := r.status.Load(); s != nil {
+		return *s
+	}
+	return RefreshStatus{}
+}
+
+// Ready reports whether at least one refresh has succeeded.
+func (r *Refresher) Ready() bool {
+	return !r.Status().LastSuccess.IsZero()
+}
+
+// Run performs an immediate refresh and then repeats on the configured
+// interval, plus a bit of jitter so that multiple instances of this process
+// don't all hit the upstream API in lockstep, until ctx is done.
+func (r *Refresher) Run(ctx context.Context) {
+	r.refreshOnce()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.interval + jitter(r.interval)):
+			r.refreshOnce()
+		}
+	}
+}
+
+func (r *Refresher) refreshOnce() {
+	data, err := gatherData(r.fetch, r.apiBaseURL)
+
+	status := RefreshStatus{LastError: err, LastSuccess: r.Status().LastSuccess}
+	if err != nil {
+		log.Printf("refresh failed: %v", err)
+	} else {
+		r.store.Set(data)
+		status.LastSuccess = time.Now()
+	}
+	r.status.Store(&status)
+}
+
+// jitter returns a random duration up to 10% of interval.
+func jitter(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(interval) / 10))
+}
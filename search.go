@@ -0,0 +1,332 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field priority weights used when ranking a match: name beats member beats
+// location beats album/year, as requested.
+const (
+	scoreName     = 100
+	scoreMember   = 70
+	scoreLocation = 50
+	scoreAlbum    = 30
+
+	// trigramMatchThreshold is the minimum Jaccard similarity between a
+	// query and an artist name's trigram sets for a fuzzy match (e.g.
+	// "queeen" -> "Queen") to count as a hit.
+	trigramMatchThreshold = 0.35
+	scoreFuzzyName        = 60
+)
+
+// firstAlbumLayout is the date format used by the upstream API's
+// "firstAlbum" field (e.g. "04-06-1970").
+const firstAlbumLayout = "02-01-2006"
+
+// SearchResult is a single reason an artist matched a search query.
+type SearchResult struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	ID    int    `json:"id"`
+}
+
+// RankedResult is one artist's aggregated search outcome: every reason it
+// matched, deduplicated per artist, with a combined Score so the best
+// matches sort first.
+type RankedResult struct {
+	ArtistID int            `json:"artistId"`
+	Name     string         `json:"name"`
+	Score    int            `json:"score"`
+	Matches  []SearchResult `json:"matches"`
+}
+
+// SearchFilters holds the structured query parameters accepted alongside
+// the free-text "q" search term.
+type SearchFilters struct {
+	Query           string
+	CreationDateMin int
+	CreationDateMax int
+	FirstAlbumFrom  time.Time
+	FirstAlbumTo    time.Time
+	Members         []string
+	Location        string
+}
+
+// parseSearchFilters reads the structured filters and free-text query out of
+// the request's query string.
+func parseSearchFilters(r *http.Request) SearchFilters {
+	q := r.URL.Query()
+
+	f := SearchFilters{
+		Query:    strings.ToLower(strings.TrimSpace(q.Get("q"))),
+		Location: strings.ToLower(strings.TrimSpace(q.Get("location"))),
+	}
+
+	f.CreationDateMin, _ = strconv.Atoi(q.Get("creationDateMin"))
+	f.CreationDateMax, _ = strconv.Atoi(q.Get("creationDateMax"))
+
+	if from, err := time.Parse(firstAlbumLayout, q.Get("firstAlbumFrom")); err == nil {
+		f.FirstAlbumFrom = from
+	}
+	if to, err := time.Parse(firstAlbumLayout, q.Get("firstAlbumTo")); err == nil {
+		f.FirstAlbumTo = to
+	}
+
+	if members := q.Get("members"); members != "" {
+		for _, m := range strings.Split(members, ",") {
+			if m = strings.ToLower(strings.TrimSpace(m)); m != "" {
+				f.Members = append(f.Members, m)
+			}
+		}
+	}
+
+	return f
+}
+
+// SearchIndex precomputes lowercase and inverted indexes over an artist
+// snapshot so Search resolves a query in roughly O(matches) instead of
+// O(artists x fields). It's rebuilt by ArtistStore.Set, i.e. at startup and
+// after every Refresher cycle.
+type SearchIndex struct {
+	artists       []map[string]interface{}
+	locationIndex map[string][]int // lowercased location slug -> artist ids
+	nameTrigrams  map[int]map[string]struct{}
+}
+
+// NewSearchIndex builds a SearchIndex over the given artist snapshot.
+func NewSearchIndex(artists []map[string]interface{}) *SearchIndex {
+	idx := &SearchIndex{
+		artists:       artists,
+		locationIndex: make(map[string][]int),
+		nameTrigrams:  make(map[int]map[string]struct{}, len(artists)),
+	}
+
+	for _, artist := range artists {
+		id := artist["Id"].(int)
+		name := artist["Name"].(string)
+		idx.nameTrigrams[id] = trigramSet(strings.ToLower(name))
+
+		for _, location := range artist["Locations"].([]string) {
+			key := strings.ToLower(location)
+			idx.locationIndex[key] = append(idx.locationIndex[key], id)
+		}
+	}
+
+	return idx
+}
+
+// Search applies f's filters to every indexed artist and returns the
+// surviving artists ranked by descending score, each carrying the list of
+// fields that made it match. Location lookups (both the ?location= filter
+// and a free-text query's location matches) are seeded from locationIndex,
+// so they cost a pass over the distinct locations rather than every
+// artist's location list.
+func (idx *SearchIndex) Search(f SearchFilters) []RankedResult {
+	if idx == nil {
+		return nil
+	}
+
+	var locationFilterIDs map[int]bool
+	if f.Location != "" {
+		locationFilterIDs = idx.matchingLocationIDs(f.Location)
+	}
+
+	var queryLocations map[int][]string
+	if f.Query != "" {
+		queryLocations = idx.matchingLocations(f.Query)
+	}
+
+	results := make([]RankedResult, 0)
+	for _, artist := range idx.artists {
+		id := artist["Id"].(int)
+
+		if locationFilterIDs != nil && !locationFilterIDs[id] {
+			continue
+		}
+		if !passesFilters(artist, f) {
+			continue
+		}
+
+		name := artist["Name"].(string)
+		matches, score := idx.matchArtist(artist, f, queryLocations[id])
+
+		if f.Query != "" && score == 0 {
+			// Filters passed but the free-text query hit nothing; this
+			// artist isn't a result.
+			continue
+		}
+
+		results = append(results, RankedResult{ArtistID: id, Name: name, Score: score, Matches: matches})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	return results
+}
+
+// matchingLocationIDs returns the set of artist ids with at least one
+// location slug containing query, scanning locationIndex's distinct keys
+// instead of every artist's location list.
+func (idx *SearchIndex) matchingLocationIDs(query string) map[int]bool {
+	ids := make(map[int]bool)
+	for loc, artistIDs := range idx.locationIndex {
+		if strings.Contains(loc, query) {
+			for _, id := range artistIDs {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
+
+// matchingLocations is like matchingLocationIDs but also keeps the matched
+// location slug(s) per artist, for reporting in SearchResult.Value.
+func (idx *SearchIndex) matchingLocations(query string) map[int][]string {
+	matches := make(map[int][]string)
+	for loc, artistIDs := range idx.locationIndex {
+		if strings.Contains(loc, query) {
+			for _, id := range artistIDs {
+				matches[id] = append(matches[id], loc)
+			}
+		}
+	}
+	return matches
+}
+
+// passesFilters reports whether artist satisfies every non-location
+// structured filter in f (location is handled via locationIndex in Search).
+// An unset filter always passes.
+func passesFilters(artist map[string]interface{}, f SearchFilters) bool {
+	creationDate := artist["CreationDate"].(int)
+	if f.CreationDateMin != 0 && creationDate < f.CreationDateMin {
+		return false
+	}
+	if f.CreationDateMax != 0 && creationDate > f.CreationDateMax {
+		return false
+	}
+
+	if !f.FirstAlbumFrom.IsZero() || !f.FirstAlbumTo.IsZero() {
+		firstAlbum, err := time.Parse(firstAlbumLayout, artist["FirstAlbum"].(string))
+		if err != nil {
+			return false
+		}
+		if !f.FirstAlbumFrom.IsZero() && firstAlbum.Before(f.FirstAlbumFrom) {
+			return false
+		}
+		if !f.FirstAlbumTo.IsZero() && firstAlbum.After(f.FirstAlbumTo) {
+			return false
+		}
+	}
+
+	if len(f.Members) > 0 {
+		members := artist["Members"].([]string)
+		for _, want := range f.Members {
+			if !anyContains(members, want) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// matchArtist scores artist against the free-text query, highest-priority
+// field first, and returns every field that matched. locations is the set
+// of this artist's location slugs that matched f.Query, precomputed by
+// Search via locationIndex.
+func (idx *SearchIndex) matchArtist(artist map[string]interface{}, f SearchFilters, locations []string) ([]SearchResult, int) {
+	if f.Query == "" {
+		return nil, 0
+	}
+
+	id := artist["Id"].(int)
+	name := artist["Name"].(string)
+	var matches []SearchResult
+	score := 0
+
+	if strings.Contains(strings.ToLower(name), f.Query) {
+		matches = append(matches, SearchResult{Type: "artist", Value: name, ID: id})
+		score += scoreName
+	} else if jaccard(trigramSet(f.Query), idx.nameTrigrams[id]) >= trigramMatchThreshold {
+		matches = append(matches, SearchResult{Type: "artist", Value: name, ID: id})
+		score += scoreFuzzyName
+	}
+
+	for _, member := range artist["Members"].([]string) {
+		if strings.Contains(strings.ToLower(member), f.Query) {
+			matches = append(matches, SearchResult{Type: "member", Value: member, ID: id})
+			score += scoreMember
+		}
+	}
+
+	for _, location := range locations {
+		matches = append(matches, SearchResult{Type: "location", Value: location, ID: id})
+		score += scoreLocation
+	}
+
+	firstAlbum := artist["FirstAlbum"].(string)
+	if strings.Contains(strings.ToLower(firstAlbum), f.Query) {
+		matches = append(matches, SearchResult{Type: "first album", Value: firstAlbum, ID: id})
+		score += scoreAlbum
+	}
+
+	creationDate := strconv.Itoa(artist["CreationDate"].(int))
+	if strings.Contains(creationDate, f.Query) {
+		matches = append(matches, SearchResult{Type: "creation date", Value: creationDate, ID: id})
+		score += scoreAlbum
+	}
+
+	return matches, score
+}
+
+// anyContains reports whether query is a substring of any value in values
+// (case-insensitively).
+func anyContains(values []string, query string) bool {
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// trigramSet returns the set of lowercase 3-character substrings of s,
+// padded with boundary markers so short words still produce trigrams.
+func trigramSet(s string) map[string]struct{} {
+	padded := "  " + s + "  "
+	set := make(map[string]struct{})
+	for i := 0; i+3 <= len(padded); i++ {
+		set[padded[i:i+3]] = struct{}{}
+	}
+	return set
+}
+
+// jaccard returns the Jaccard similarity |a n b| / |a u b| between two
+// trigram sets.
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for t := range a {
+		if _, ok := b[t]; ok {
+			intersection++
+		}
+	}
+
+	union := len(a) + len(b) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}